@@ -0,0 +1,146 @@
+// Package agent drives a ToolCallingChatModel through a generate/execute-tools
+// loop: it calls Generate, dispatches any returned tool calls to their
+// registered handlers, appends the results to the conversation, and calls
+// Generate again until the model stops requesting tools or MaxSteps is hit.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const defaultMaxSteps = 5
+
+// ToolHandler executes a single tool call given its JSON-encoded arguments and
+// returns the tool's textual result.
+type ToolHandler func(ctx context.Context, jsonArgs string) (string, error)
+
+// ConfirmFunc is consulted before a tool call is executed. Returning false
+// skips the call and reports the denial back to the model instead of running
+// the handler.
+type ConfirmFunc func(ctx context.Context, call schema.ToolCall) bool
+
+// Agent wraps a ToolCallingChatModel with a registry of tool handlers and
+// drives the generate/execute loop in Run.
+type Agent struct {
+	llm      model.ToolCallingChatModel
+	infos    []*schema.ToolInfo
+	handlers map[string]ToolHandler
+	maxSteps int
+	confirm  ConfirmFunc
+}
+
+// Option configures an Agent built by NewAgent.
+type Option func(*Agent)
+
+// WithTools registers the tools the agent may call, keyed by tool name, and
+// binds their descriptors to the underlying model via WithTools so every
+// Generate call advertises them.
+func WithTools(infos []*schema.ToolInfo, handlers map[string]ToolHandler) Option {
+	return func(a *Agent) {
+		a.infos = infos
+		a.handlers = handlers
+	}
+}
+
+// WithMaxSteps bounds the number of generate/execute rounds before Run gives
+// up and returns an error. Defaults to 5.
+func WithMaxSteps(maxSteps int) Option {
+	return func(a *Agent) {
+		a.maxSteps = maxSteps
+	}
+}
+
+// WithConfirm installs a hook that is asked to approve each tool call before
+// it runs, so callers can surface a confirmation prompt to the user.
+func WithConfirm(confirm ConfirmFunc) Option {
+	return func(a *Agent) {
+		a.confirm = confirm
+	}
+}
+
+// NewAgent builds an Agent around llm, applying opts and binding any
+// registered tools to the model.
+func NewAgent(llm model.ToolCallingChatModel, opts ...Option) (*Agent, error) {
+	a := &Agent{
+		llm:      llm,
+		handlers: map[string]ToolHandler{},
+		maxSteps: defaultMaxSteps,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if len(a.infos) > 0 {
+		bound, err := a.llm.WithTools(a.infos)
+		if err != nil {
+			return nil, fmt.Errorf("agent: bind tools: %w", err)
+		}
+		a.llm = bound
+	}
+
+	return a, nil
+}
+
+// Run drives the generate/execute-tools loop starting from in and returns the
+// model's final, tool-call-free message.
+func (a *Agent) Run(ctx context.Context, in []*schema.Message) (*schema.Message, error) {
+	history := append([]*schema.Message{}, in...)
+
+	for step := 0; step < a.maxSteps; step++ {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("agent run canceled: %w", ctx.Err())
+		default:
+		}
+
+		log.Printf("agent step %d/%d: generating", step+1, a.maxSteps)
+		out, err := a.llm.Generate(ctx, history)
+		if err != nil {
+			return nil, fmt.Errorf("agent generate failed at step %d: %w", step+1, err)
+		}
+
+		if len(out.ToolCalls) == 0 {
+			return out, nil
+		}
+
+		history = append(history, out)
+		for _, call := range out.ToolCalls {
+			result := a.executeToolCall(ctx, call)
+			history = append(history, schema.ToolMessage(result, call.ID))
+		}
+	}
+
+	return nil, fmt.Errorf("agent exceeded max steps (%d) without a final answer", a.maxSteps)
+}
+
+// executeToolCall resolves the handler for call, asks the confirmation hook
+// (if any) for approval, and runs it. Denials and handler errors are reported
+// as the tool result rather than surfaced as errors, so the model can react to
+// them on its next turn.
+func (a *Agent) executeToolCall(ctx context.Context, call schema.ToolCall) string {
+	name := call.Function.Name
+
+	if a.confirm != nil && !a.confirm(ctx, call) {
+		log.Printf("tool call %q (%s) denied by confirmation hook", name, call.ID)
+		return "tool call denied by user"
+	}
+
+	handler, ok := a.handlers[name]
+	if !ok {
+		log.Printf("no handler registered for tool %q", name)
+		return fmt.Sprintf("error: no handler registered for tool %q", name)
+	}
+
+	log.Printf("executing tool %q (%s) with args: %s", name, call.ID, call.Function.Arguments)
+	result, err := handler(ctx, call.Function.Arguments)
+	if err != nil {
+		log.Printf("tool %q failed: %v", name, err)
+		return fmt.Sprintf("error: tool %q failed: %v", name, err)
+	}
+	return result
+}
@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mockToolCallingChatModel struct {
+	generateFn func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error)
+	streamFn   func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error)
+}
+
+func (m *mockToolCallingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if m.generateFn != nil {
+		return m.generateFn(ctx, input, opts...)
+	}
+	return nil, errors.New("generateFn not set")
+}
+
+func (m *mockToolCallingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if m.streamFn != nil {
+		return m.streamFn(ctx, input, opts...)
+	}
+	return nil, errors.New("streamFn not set")
+}
+
+func (m *mockToolCallingChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+func TestAgentRun_NoToolCalls(t *testing.T) {
+	final := schema.AssistantMessage("hello there", nil)
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+			return final, nil
+		},
+	}
+
+	a, err := NewAgent(mockModel)
+	if err != nil {
+		t.Fatalf("NewAgent returned unexpected error: %v", err)
+	}
+
+	got, err := a.Run(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+	if got != final {
+		t.Fatalf("expected message %p, got %p", final, got)
+	}
+}
+
+func TestAgentRun_DispatchesToolCall(t *testing.T) {
+	toolCall := schema.ToolCall{
+		ID:       "call-1",
+		Function: schema.FunctionCall{Name: "echo", Arguments: `{"text":"hi"}`},
+	}
+	withCall := schema.AssistantMessage("", []schema.ToolCall{toolCall})
+	final := schema.AssistantMessage("done", nil)
+
+	var calls atomic.Int32
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(ctx context.Context, in []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+			if calls.Add(1) == 1 {
+				return withCall, nil
+			}
+			last := in[len(in)-1]
+			if last.ToolCallID != "call-1" || last.Content != "echoed: hi" {
+				t.Fatalf("unexpected tool result message: %+v", last)
+			}
+			return final, nil
+		},
+	}
+
+	handled := false
+	handlers := map[string]ToolHandler{
+		"echo": func(ctx context.Context, jsonArgs string) (string, error) {
+			handled = true
+			return "echoed: hi", nil
+		},
+	}
+
+	a, err := NewAgent(mockModel, WithTools([]*schema.ToolInfo{{Name: "echo"}}, handlers))
+	if err != nil {
+		t.Fatalf("NewAgent returned unexpected error: %v", err)
+	}
+
+	got, err := a.Run(context.Background(), []*schema.Message{schema.UserMessage("hi")})
+	if err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+	if got != final {
+		t.Fatalf("expected message %p, got %p", final, got)
+	}
+	if !handled {
+		t.Fatalf("expected echo handler to run")
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 generate calls, got %d", calls.Load())
+	}
+}
+
+func TestAgentRun_ConfirmDenies(t *testing.T) {
+	toolCall := schema.ToolCall{ID: "call-1", Function: schema.FunctionCall{Name: "echo"}}
+	withCall := schema.AssistantMessage("", []schema.ToolCall{toolCall})
+	final := schema.AssistantMessage("ok", nil)
+
+	var calls atomic.Int32
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(ctx context.Context, in []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+			if calls.Add(1) == 1 {
+				return withCall, nil
+			}
+			last := in[len(in)-1]
+			if last.Content != "tool call denied by user" {
+				t.Fatalf("expected denial message, got %q", last.Content)
+			}
+			return final, nil
+		},
+	}
+
+	handlerCalled := false
+	handlers := map[string]ToolHandler{
+		"echo": func(ctx context.Context, jsonArgs string) (string, error) {
+			handlerCalled = true
+			return "should not run", nil
+		},
+	}
+
+	a, err := NewAgent(mockModel,
+		WithTools([]*schema.ToolInfo{{Name: "echo"}}, handlers),
+		WithConfirm(func(ctx context.Context, call schema.ToolCall) bool { return false }),
+	)
+	if err != nil {
+		t.Fatalf("NewAgent returned unexpected error: %v", err)
+	}
+
+	if _, err := a.Run(context.Background(), []*schema.Message{schema.UserMessage("hi")}); err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+	if handlerCalled {
+		t.Fatalf("expected handler not to run when confirmation is denied")
+	}
+}
+
+func TestAgentRun_MaxStepsExceeded(t *testing.T) {
+	toolCall := schema.ToolCall{ID: "call-1", Function: schema.FunctionCall{Name: "echo"}}
+	withCall := schema.AssistantMessage("", []schema.ToolCall{toolCall})
+
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+			return withCall, nil
+		},
+	}
+
+	handlers := map[string]ToolHandler{
+		"echo": func(ctx context.Context, jsonArgs string) (string, error) {
+			return "result", nil
+		},
+	}
+
+	a, err := NewAgent(mockModel, WithTools([]*schema.ToolInfo{{Name: "echo"}}, handlers), WithMaxSteps(2))
+	if err != nil {
+		t.Fatalf("NewAgent returned unexpected error: %v", err)
+	}
+
+	if _, err := a.Run(context.Background(), []*schema.Message{schema.UserMessage("hi")}); err == nil {
+		t.Fatalf("expected error when max steps is exceeded")
+	}
+}
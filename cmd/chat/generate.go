@@ -2,21 +2,22 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"io"
 	"log"
-	"net"
 	"time"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
-var retryBaseDelay = time.Second
+// defaultRetrier wraps llm calls with the default backoff and circuit
+// breaker policy, keyed per modelName.
+var defaultRetrier = NewRetrier(DefaultRetryPolicy())
 
-func generate(ctx context.Context, llm model.ToolCallingChatModel, in []*schema.Message) (*schema.Message, error) {
+func generate(ctx context.Context, modelName string, llm model.ToolCallingChatModel, in []*schema.Message) (*InvokeResponse, error) {
 	start := time.Now()
-	out, err := llm.Generate(ctx, in)
+	out, err := defaultRetrier.Generate(ctx, modelName, llm, in)
 	duration := time.Since(start)
 
 	if err != nil {
@@ -24,37 +25,57 @@ func generate(ctx context.Context, llm model.ToolCallingChatModel, in []*schema.
 		return nil, fmt.Errorf("llm generate failed: %w", err)
 	}
 
-	log.Printf("generate completed in %v", duration)
-	return out, nil
+	resp := newInvokeResponse(out)
+	defaultUsageCollector.Add(resp.TokenUsage)
+	log.Printf("generate completed in %v (tokens: prompt=%d completion=%d total=%d)",
+		duration, resp.TokenUsage.PromptTokens, resp.TokenUsage.CompletionTokens, resp.TokenUsage.TotalTokens)
+	return resp, nil
 }
 
-func generateWithRetry(ctx context.Context, llm model.ToolCallingChatModel, in []*schema.Message) (*schema.Message, error) {
-	const maxRetries = 3
-
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		out, err := llm.Generate(ctx, in)
-		if err == nil {
-			if attempt > 0 {
-				log.Printf("generate succeeded on retry attempt %d", attempt+1)
-			}
-			return out, nil
+// generateStream drives a streamed Generate call, forwarding content chunks
+// to writer as they arrive, and returns the same structured InvokeResponse
+// shape as generate once the stream completes. Establishing the stream and
+// draining it both run under defaultRetrier, so a retryable failure at
+// either step is retried and counts toward modelName's circuit breaker.
+func generateStream(ctx context.Context, modelName string, llm model.ToolCallingChatModel, in []*schema.Message, writer io.Writer) (*InvokeResponse, error) {
+	start := time.Now()
+	out, err := defaultRetrier.Do(ctx, modelName, func(ctx context.Context) (*schema.Message, error) {
+		sr, err := stream(ctx, llm, in)
+		if err != nil {
+			return nil, err
 		}
+		return reportStreamWithContext(ctx, sr, writer)
+	})
+	duration := time.Since(start)
+	if err != nil {
+		log.Printf("generateStream failed after %v: %v", duration, err)
+		return nil, err
+	}
+
+	resp := newInvokeResponse(out)
+	defaultUsageCollector.Add(resp.TokenUsage)
+	log.Printf("generateStream completed in %v (tokens: prompt=%d completion=%d total=%d)",
+		duration, resp.TokenUsage.PromptTokens, resp.TokenUsage.CompletionTokens, resp.TokenUsage.TotalTokens)
+	return resp, nil
+}
 
-		if isRetryableError(err) && attempt < maxRetries-1 {
-			delay := retryBaseDelay * time.Duration(attempt+1)
-			log.Printf("generate attempt %d failed: %v, retrying in %v", attempt+1, err, delay)
-			select {
-			case <-time.After(delay):
-			case <-ctx.Done():
-				return nil, fmt.Errorf("generate retry canceled: %w", ctx.Err())
+// newInvokeResponse builds an InvokeResponse from a model message, pulling
+// finish reason and token usage out of its ResponseMeta when present.
+func newInvokeResponse(msg *schema.Message) *InvokeResponse {
+	resp := &InvokeResponse{Message: msg, ToolCalls: msg.ToolCalls}
+
+	if msg.ResponseMeta != nil {
+		resp.FinishReason = msg.ResponseMeta.FinishReason
+		if usage := msg.ResponseMeta.Usage; usage != nil {
+			resp.TokenUsage = TokenUsage{
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+				TotalTokens:      usage.TotalTokens,
 			}
-			continue
 		}
-
-		return nil, fmt.Errorf("after %d retries: %w", attempt+1, err)
 	}
 
-	return nil, errors.New("generateWithRetry: unexpected error")
+	return resp
 }
 
 func stream(ctx context.Context, llm model.ToolCallingChatModel, in []*schema.Message) (*schema.StreamReader[*schema.Message], error) {
@@ -70,16 +91,3 @@ func stream(ctx context.Context, llm model.ToolCallingChatModel, in []*schema.Me
 	log.Printf("stream started in %v", duration)
 	return stream, nil
 }
-
-func isRetryableError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	var netErr net.Error
-	if errors.As(err, &netErr) {
-		return netErr.Timeout() || netErr.Temporary()
-	}
-
-	return false
-}
@@ -3,18 +3,16 @@ package main
 import (
 	"context"
 	"errors"
-	"net"
-	"sync/atomic"
+	"io"
 	"testing"
-	"time"
 
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
 type mockToolCallingChatModel struct {
-	generateFn func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error)
-	streamFn   func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error)
+	generateFn  func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error)
+	streamFn    func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error)
 	withToolsFn func([]*schema.ToolInfo) (model.ToolCallingChatModel, error)
 }
 
@@ -57,13 +55,13 @@ func TestGenerateSuccess(t *testing.T) {
 		},
 	}
 
-	got, err := generate(context.Background(), mockModel, nil)
+	got, err := generate(context.Background(), "gpt-test", mockModel, nil)
 	if err != nil {
 		t.Fatalf("generate returned unexpected error: %v", err)
 	}
 
-	if got != message {
-		t.Fatalf("expected message %p, got %p", message, got)
+	if got.Message != message {
+		t.Fatalf("expected message %p, got %p", message, got.Message)
 	}
 }
 
@@ -75,7 +73,7 @@ func TestGenerateError(t *testing.T) {
 		},
 	}
 
-	got, err := generate(context.Background(), mockModel, nil)
+	got, err := generate(context.Background(), "gpt-test", mockModel, nil)
 	if got != nil {
 		t.Fatalf("expected nil message, got %v", got)
 	}
@@ -84,145 +82,119 @@ func TestGenerateError(t *testing.T) {
 	}
 }
 
-func TestGenerateWithRetry_SucceedsAfterRetry(t *testing.T) {
-	message := schema.AssistantMessage("retry", nil)
-	var calls atomic.Int32
-	firstErr := &fakeNetError{msg: "temporary", temporary: true}
-
+func TestStreamSuccess(t *testing.T) {
+	reader, writer := schema.Pipe[*schema.Message](1)
+	writer.Close()
 	mockModel := &mockToolCallingChatModel{
-		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
-			if calls.Add(1) == 1 {
-				return nil, firstErr
-			}
-			return message, nil
+		streamFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+			return reader, nil
 		},
 	}
 
-	prevDelay := retryBaseDelay
-	retryBaseDelay = time.Millisecond
-	t.Cleanup(func() {
-		retryBaseDelay = prevDelay
-	})
-
-	got, err := generateWithRetry(context.Background(), mockModel, nil)
+	got, err := stream(context.Background(), mockModel, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if got != message {
-		t.Fatalf("expected message %p, got %p", message, got)
-	}
-	if calls.Load() != 2 {
-		t.Fatalf("expected 2 generate attempts, got %d", calls.Load())
+	if got != reader {
+		t.Fatalf("expected reader %p, got %p", reader, got)
 	}
 }
 
-func TestGenerateWithRetry_NonRetryable(t *testing.T) {
-	baseErr := errors.New("fatal")
-	var calls atomic.Int32
+func TestStreamError(t *testing.T) {
+	baseErr := errors.New("stream error")
 	mockModel := &mockToolCallingChatModel{
-		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
-			calls.Add(1)
+		streamFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
 			return nil, baseErr
 		},
 	}
 
-	_, err := generateWithRetry(context.Background(), mockModel, nil)
+	got, err := stream(context.Background(), mockModel, nil)
+	if got != nil {
+		t.Fatalf("expected nil stream reader, got %v", got)
+	}
 	if err == nil || !errors.Is(err, baseErr) {
 		t.Fatalf("expected error wrapping baseErr, got %v", err)
 	}
-	if calls.Load() != 1 {
-		t.Fatalf("expected 1 generate attempt, got %d", calls.Load())
-	}
 }
 
-func TestGenerateWithRetry_ContextCanceled(t *testing.T) {
-	var calls atomic.Int32
+func TestGenerate_AggregatesTokenUsage(t *testing.T) {
+	prevCollector := defaultUsageCollector
+	defaultUsageCollector = NewUsageCollector()
+	t.Cleanup(func() { defaultUsageCollector = prevCollector })
+
+	message := schema.AssistantMessage("hi", nil)
+	message.ResponseMeta = &schema.ResponseMeta{
+		FinishReason: "stop",
+		Usage:        &schema.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}
 	mockModel := &mockToolCallingChatModel{
 		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
-			calls.Add(1)
-			return nil, &fakeNetError{msg: "timeout", timeout: true}
+			return message, nil
 		},
 	}
 
-	prevDelay := retryBaseDelay
-	retryBaseDelay = time.Millisecond
-	t.Cleanup(func() {
-		retryBaseDelay = prevDelay
-	})
-
-	ctx, cancel := context.WithCancel(context.Background())
-	go func() {
-		// ensure cancellation happens after first attempt
-		time.Sleep(time.Millisecond)
-		cancel()
-	}()
-
-	_, err := generateWithRetry(ctx, mockModel, nil)
-	if err == nil || !errors.Is(err, context.Canceled) {
-		t.Fatalf("expected context canceled error, got %v", err)
-	}
-	if calls.Load() == 0 {
-		t.Fatalf("expected at least one generate attempt")
+	for i := 0; i < 2; i++ {
+		got, err := generate(context.Background(), "gpt-test", mockModel, nil)
+		if err != nil {
+			t.Fatalf("generate returned unexpected error: %v", err)
+		}
+		if got.FinishReason != "stop" {
+			t.Fatalf("expected finish reason %q, got %q", "stop", got.FinishReason)
+		}
+		if got.TokenUsage != (TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}) {
+			t.Fatalf("unexpected token usage: %+v", got.TokenUsage)
+		}
 	}
-}
 
-func TestStreamSuccess(t *testing.T) {
-	reader, writer := schema.Pipe[*schema.Message](1)
-	writer.Close()
-	mockModel := &mockToolCallingChatModel{
-		streamFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
-			return reader, nil
-		},
+	want := TokenUsage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30}
+	if got := defaultUsageCollector.Totals(); got != want {
+		t.Fatalf("expected aggregated totals %+v, got %+v", want, got)
 	}
+}
 
-	got, err := stream(context.Background(), mockModel, nil)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if got != reader {
-		t.Fatalf("expected reader %p, got %p", reader, got)
+func TestGenerateStream_AggregatesTokenUsage(t *testing.T) {
+	prevCollector := defaultUsageCollector
+	defaultUsageCollector = NewUsageCollector()
+	t.Cleanup(func() { defaultUsageCollector = prevCollector })
+
+	newReader := func() *schema.StreamReader[*schema.Message] {
+		reader, writer := schema.Pipe[*schema.Message](2)
+		go func() {
+			defer writer.Close()
+			writer.Send(schema.AssistantMessage("chunk one ", nil), nil)
+			writer.Send(&schema.Message{
+				Role:    schema.Assistant,
+				Content: "chunk two",
+				ResponseMeta: &schema.ResponseMeta{
+					FinishReason: "stop",
+					Usage:        &schema.TokenUsage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10},
+				},
+			}, nil)
+		}()
+		return reader
 	}
-}
 
-func TestStreamError(t *testing.T) {
-	baseErr := errors.New("stream error")
 	mockModel := &mockToolCallingChatModel{
 		streamFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
-			return nil, baseErr
+			return newReader(), nil
 		},
 	}
 
-	got, err := stream(context.Background(), mockModel, nil)
-	if got != nil {
-		t.Fatalf("expected nil stream reader, got %v", got)
-	}
-	if err == nil || !errors.Is(err, baseErr) {
-		t.Fatalf("expected error wrapping baseErr, got %v", err)
+	for i := 0; i < 2; i++ {
+		got, err := generateStream(context.Background(), "gpt-test", mockModel, nil, io.Discard)
+		if err != nil {
+			t.Fatalf("generateStream returned unexpected error: %v", err)
+		}
+		if got.Message.Content != "chunk one chunk two" {
+			t.Fatalf("unexpected aggregated content: %q", got.Message.Content)
+		}
+		if got.TokenUsage != (TokenUsage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10}) {
+			t.Fatalf("unexpected token usage: %+v", got.TokenUsage)
+		}
 	}
-}
 
-func TestIsRetryableError(t *testing.T) {
-	tests := []struct {
-		name string
-		err  error
-		want bool
-	}{
-		{"nil", nil, false},
-		{"timeout", &fakeNetError{timeout: true}, true},
-		{"temporary", &fakeNetError{temporary: true}, true},
-		{"nonRetryableNet", &fakeNetError{}, false},
-		{"generic", errors.New("other"), false},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := isRetryableError(tt.err); got != tt.want {
-				t.Fatalf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
-			}
-		})
+	want := TokenUsage{PromptTokens: 14, CompletionTokens: 6, TotalTokens: 20}
+	if got := defaultUsageCollector.Totals(); got != want {
+		t.Fatalf("expected aggregated totals %+v, got %+v", want, got)
 	}
 }
-
-func TestIsRetryableError_ImplementsNetError(t *testing.T) {
-	var _ net.Error = &fakeNetError{}
-}
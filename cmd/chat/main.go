@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 )
 
 func main() {
@@ -10,17 +11,23 @@ func main() {
 
 	messages := createMessagesFromTemplate()
 
-	cm := createOpenAIChatModel(ctx)
+	cfg, err := LoadModelConfig()
+	if err != nil {
+		log.Fatalf("failed to load model config: %v", err)
+	}
+	cm := createChatModelWithConfig(ctx, cfg)
 
-	result, err := generate(ctx, cm, messages)
+	result, err := generate(ctx, cfg.Model, cm, messages)
 	if err != nil {
 		log.Fatalf("generate failed: %v", err)
 	}
-	log.Printf("generate result: %v", result)
+	log.Printf("generate result: %v", result.Message)
 
-	sr, err := stream(ctx, cm, messages)
+	final, err := generateStream(ctx, cfg.Model, cm, messages, os.Stdout)
 	if err != nil {
-		log.Fatalf("stream failed: %v", err)
+		log.Fatalf("generate stream failed: %v", err)
 	}
-	reportStream(sr)
+	log.Printf("generate stream result: %v", final.Message)
+
+	log.Printf("session token usage: %+v", DefaultUsageCollector().Totals())
 }
@@ -8,27 +8,40 @@ import (
 	"os"
 	"time"
 
-	"github.com/cloudwego/eino-ext/components/model/openai"
 	"github.com/cloudwego/eino/components/model"
 )
 
+// AzureConfig holds the settings specific to the azure-openai provider.
+type AzureConfig struct {
+	APIVersion string
+}
+
 // ModelConfig 模型配置
 type ModelConfig struct {
+	Provider    string
 	APIKey      string
 	Model       string
 	BaseURL     string
 	Temperature *float32
 	Timeout     time.Duration
 	MaxRetries  int
+	MaxTokens   int
+	Azure       AzureConfig
 }
 
+// defaultMaxTokens is used when the provider's max-tokens env var is unset.
+// Anthropic's Messages API requires a non-zero max_tokens on every call.
+const defaultMaxTokens = 1024
+
 // DefaultModelConfig 返回默认模型配置
 func DefaultModelConfig() *ModelConfig {
 	temperature := float32(0.7) // 默认创造性温度
 	return &ModelConfig{
+		Provider:    defaultProviderName,
 		Temperature: &temperature,
 		Timeout:     30 * time.Second,
 		MaxRetries:  3,
+		MaxTokens:   defaultMaxTokens,
 	}
 }
 
@@ -36,18 +49,49 @@ func DefaultModelConfig() *ModelConfig {
 func LoadModelConfig() (*ModelConfig, error) {
 	config := DefaultModelConfig()
 
-	// API密钥验证
-	if key := os.Getenv("OPENAI_API_KEY"); key == "" {
-		return nil, errors.New("OPENAI_API_KEY environment variable is required")
-	} else {
-		config.APIKey = key
+	// 提供商选择，留空则回退到 openai 以保持向后兼容
+	if provider := os.Getenv("EINO_PROVIDER"); provider != "" {
+		config.Provider = provider
 	}
 
-	// 模型名称验证
-	if modelName := os.Getenv("OPENAI_MODEL_NAME"); modelName == "" {
-		return nil, errors.New("OPENAI_MODEL_NAME environment variable is required")
-	} else {
+	// 密钥与模型名称因提供商而异：ollama 本地运行无需密钥，
+	// anthropic 使用自己的环境变量，其余（openai/azure-openai）沿用 OPENAI_*。
+	switch config.Provider {
+	case "ollama":
+		modelName := firstNonEmptyEnv("OLLAMA_MODEL_NAME", "OPENAI_MODEL_NAME")
+		if modelName == "" {
+			return nil, errors.New("OLLAMA_MODEL_NAME environment variable is required")
+		}
+		config.Model = modelName
+
+	case "anthropic":
+		if key := os.Getenv("ANTHROPIC_API_KEY"); key == "" {
+			return nil, errors.New("ANTHROPIC_API_KEY environment variable is required")
+		} else {
+			config.APIKey = key
+		}
+		modelName := firstNonEmptyEnv("ANTHROPIC_MODEL_NAME", "OPENAI_MODEL_NAME")
+		if modelName == "" {
+			return nil, errors.New("ANTHROPIC_MODEL_NAME environment variable is required")
+		}
 		config.Model = modelName
+		if maxTokensStr := os.Getenv("ANTHROPIC_MAX_TOKENS"); maxTokensStr != "" {
+			if _, err := fmt.Sscanf(maxTokensStr, "%d", &config.MaxTokens); err != nil {
+				return nil, fmt.Errorf("invalid ANTHROPIC_MAX_TOKENS value: %w", err)
+			}
+		}
+
+	default: // openai, azure-openai
+		if key := os.Getenv("OPENAI_API_KEY"); key == "" {
+			return nil, errors.New("OPENAI_API_KEY environment variable is required")
+		} else {
+			config.APIKey = key
+		}
+		if modelName := os.Getenv("OPENAI_MODEL_NAME"); modelName == "" {
+			return nil, errors.New("OPENAI_MODEL_NAME environment variable is required")
+		} else {
+			config.Model = modelName
+		}
 	}
 
 	// 可选的 BaseURL
@@ -80,25 +124,37 @@ func LoadModelConfig() (*ModelConfig, error) {
 		}
 	}
 
+	// 可选的 Azure API 版本
+	if apiVersion := os.Getenv("AZURE_OPENAI_API_VERSION"); apiVersion != "" {
+		config.Azure.APIVersion = apiVersion
+	}
+
 	return config, nil
 }
 
-func createOpenAIChatModel(ctx context.Context) model.ToolCallingChatModel {
-	return createOpenAIChatModelWithConfig(ctx, nil)
+// firstNonEmptyEnv returns the value of the first of names that is set and
+// non-empty, or "" if none are.
+func firstNonEmptyEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
 }
 
-func createOpenAIChatModelWithConfig(ctx context.Context, customConfig *ModelConfig) model.ToolCallingChatModel {
-	var config *ModelConfig
-	var err error
-
-	// 使用自定义配置或从环境变量加载
-	if customConfig != nil {
-		config = customConfig
-	} else {
-		config, err = LoadModelConfig()
-		if err != nil {
-			log.Fatalf("failed to load model config: %v", err)
-		}
+// createChatModelWithConfig builds a model.ToolCallingChatModel using the
+// provider named by config.Provider, retrying creation up to
+// config.MaxRetries times.
+func createChatModelWithConfig(ctx context.Context, config *ModelConfig) model.ToolCallingChatModel {
+	providerName := config.Provider
+	if providerName == "" {
+		providerName = defaultProviderName
+	}
+
+	provider, err := LookupProvider(providerName)
+	if err != nil {
+		log.Fatalf("failed to resolve model provider: %v", err)
 	}
 
 	// 创建上下文，包含超时
@@ -108,12 +164,7 @@ func createOpenAIChatModelWithConfig(ctx context.Context, customConfig *ModelCon
 	// 带重试的模型创建
 	var chatModel model.ToolCallingChatModel
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
-		chatModel, err = openai.NewChatModel(ctxWithTimeout, &openai.ChatModelConfig{
-			APIKey:      config.APIKey,
-			Model:       config.Model,
-			BaseURL:     config.BaseURL,
-			Temperature: config.Temperature,
-		})
+		chatModel, err = provider.New(ctxWithTimeout, config)
 
 		if err == nil {
 			if attempt > 0 {
@@ -124,7 +175,7 @@ func createOpenAIChatModelWithConfig(ctx context.Context, customConfig *ModelCon
 
 		// 如果是最后一次尝试，记录错误
 		if attempt == config.MaxRetries {
-			log.Fatalf("failed to create openai chat model after %d attempts: %v", config.MaxRetries+1, err)
+			log.Fatalf("failed to create %s chat model after %d attempts: %v", providerName, config.MaxRetries+1, err)
 		}
 
 		// 等待后重试
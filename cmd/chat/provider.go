@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/model/claude"
+	"github.com/cloudwego/eino-ext/components/model/ollama"
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+)
+
+// defaultProviderName is used when ModelConfig.Provider is unset, keeping
+// existing OPENAI_* configurations working without changes.
+const defaultProviderName = "openai"
+
+// Provider builds a model.ToolCallingChatModel for one chat backend.
+type Provider interface {
+	// Name is the registry key used by ModelConfig.Provider / EINO_PROVIDER.
+	Name() string
+	// New constructs the chat model from cfg.
+	New(ctx context.Context, cfg *ModelConfig) (model.ToolCallingChatModel, error)
+}
+
+var providers = map[string]Provider{}
+
+// RegisterProvider adds p to the registry under p.Name(), replacing any
+// provider previously registered with the same name.
+func RegisterProvider(p Provider) {
+	providers[p.Name()] = p
+}
+
+// LookupProvider returns the provider registered as name.
+func LookupProvider(name string) (Provider, error) {
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+	return p, nil
+}
+
+func init() {
+	RegisterProvider(openAIProvider{})
+	RegisterProvider(azureOpenAIProvider{})
+	RegisterProvider(ollamaProvider{})
+	RegisterProvider(anthropicProvider{})
+}
+
+type openAIProvider struct{}
+
+func (openAIProvider) Name() string { return "openai" }
+
+func (openAIProvider) New(ctx context.Context, cfg *ModelConfig) (model.ToolCallingChatModel, error) {
+	return openai.NewChatModel(ctx, &openai.ChatModelConfig{
+		APIKey:      cfg.APIKey,
+		Model:       cfg.Model,
+		BaseURL:     cfg.BaseURL,
+		Temperature: cfg.Temperature,
+	})
+}
+
+type azureOpenAIProvider struct{}
+
+func (azureOpenAIProvider) Name() string { return "azure-openai" }
+
+func (azureOpenAIProvider) New(ctx context.Context, cfg *ModelConfig) (model.ToolCallingChatModel, error) {
+	return openai.NewChatModel(ctx, &openai.ChatModelConfig{
+		APIKey:      cfg.APIKey,
+		Model:       cfg.Model,
+		BaseURL:     cfg.BaseURL,
+		Temperature: cfg.Temperature,
+		ByAzure:     true,
+		APIVersion:  cfg.Azure.APIVersion,
+	})
+}
+
+type ollamaProvider struct{}
+
+func (ollamaProvider) Name() string { return "ollama" }
+
+func (ollamaProvider) New(ctx context.Context, cfg *ModelConfig) (model.ToolCallingChatModel, error) {
+	return ollama.NewChatModel(ctx, &ollama.ChatModelConfig{
+		BaseURL: cfg.BaseURL,
+		Model:   cfg.Model,
+	})
+}
+
+type anthropicProvider struct{}
+
+func (anthropicProvider) Name() string { return "anthropic" }
+
+func (anthropicProvider) New(ctx context.Context, cfg *ModelConfig) (model.ToolCallingChatModel, error) {
+	return claude.NewChatModel(ctx, &claude.Config{
+		APIKey:    cfg.APIKey,
+		Model:     cfg.Model,
+		BaseURL:   cfg.BaseURL,
+		MaxTokens: cfg.MaxTokens,
+	})
+}
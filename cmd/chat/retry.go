@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrCircuitOpen is returned by Retrier.Generate when the circuit breaker for
+// a model is open and short-circuiting calls.
+var ErrCircuitOpen = errors.New("retrier: circuit breaker open")
+
+// RetryPolicy configures a Retrier's backoff and circuit breaker behavior.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit breaker.
+	FailureThreshold int
+	// Window bounds how long a streak of failures may span before it resets;
+	// failures further apart than Window don't compound toward the threshold.
+	Window time.Duration
+	// CooldownPeriod is how long the breaker stays open before allowing a
+	// single half-open probe call through.
+	CooldownPeriod time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used when none is supplied.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:       3,
+		BaseDelay:        time.Second,
+		MaxDelay:         30 * time.Second,
+		FailureThreshold: 5,
+		Window:           time.Minute,
+		CooldownPeriod:   30 * time.Second,
+	}
+}
+
+// clock abstracts time so tests can drive backoff and the circuit breaker
+// deterministically.
+type clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker opens after FailureThreshold consecutive failures within
+// Window and short-circuits further calls until CooldownPeriod elapses, at
+// which point it lets a single half-open probe through.
+type circuitBreaker struct {
+	mu sync.Mutex
+
+	policy RetryPolicy
+	clock  clock
+
+	state            breakerState
+	consecutiveFails int
+	firstFailureAt   time.Time
+	openedAt         time.Time
+}
+
+func newCircuitBreaker(policy RetryPolicy, c clock) *circuitBreaker {
+	return &circuitBreaker{policy: policy, clock: c}
+}
+
+// allow reports whether a call may proceed, transitioning an open breaker to
+// half-open once the cooldown has elapsed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerHalfOpen:
+		// the single probe is already in flight; hold further calls until it
+		// reports success or failure.
+		return false
+	}
+	if b.clock.Now().Sub(b.openedAt) < b.policy.CooldownPeriod {
+		return false
+	}
+
+	b.state = breakerHalfOpen
+	return true
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.consecutiveFails = 0
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+
+	if b.state == breakerHalfOpen {
+		// the probe call failed: re-open immediately without counting
+		// further toward the threshold.
+		b.state = breakerOpen
+		b.openedAt = now
+		b.consecutiveFails = 0
+		return
+	}
+
+	if b.consecutiveFails == 0 || now.Sub(b.firstFailureAt) > b.policy.Window {
+		b.firstFailureAt = now
+		b.consecutiveFails = 0
+	}
+	b.consecutiveFails++
+
+	if b.consecutiveFails >= b.policy.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = now
+		b.consecutiveFails = 0
+	}
+}
+
+// Retrier wraps Generate calls with full-jitter exponential backoff and a
+// circuit breaker kept per model name.
+type Retrier struct {
+	policy RetryPolicy
+	clock  clock
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// NewRetrier builds a Retrier following policy.
+func NewRetrier(policy RetryPolicy) *Retrier {
+	return &Retrier{policy: policy, clock: realClock{}, breakers: map[string]*circuitBreaker{}}
+}
+
+func (r *Retrier) breakerFor(modelName string) *circuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[modelName]
+	if !ok {
+		b = newCircuitBreaker(r.policy, r.clock)
+		r.breakers[modelName] = b
+	}
+	return b
+}
+
+// Generate calls llm.Generate, retrying retryable errors with full-jitter
+// exponential backoff, and short-circuits with ErrCircuitOpen once modelName
+// has tripped its circuit breaker.
+func (r *Retrier) Generate(ctx context.Context, modelName string, llm model.ToolCallingChatModel, in []*schema.Message) (*schema.Message, error) {
+	return r.Do(ctx, modelName, func(ctx context.Context) (*schema.Message, error) {
+		return llm.Generate(ctx, in)
+	})
+}
+
+// Do runs fn under the same retry and circuit breaker policy as Generate, so
+// callers with a different call shape (e.g. establishing a stream rather
+// than a single Generate) still benefit from backoff and per-model tripping.
+func (r *Retrier) Do(ctx context.Context, modelName string, fn func(ctx context.Context) (*schema.Message, error)) (*schema.Message, error) {
+	breaker := r.breakerFor(modelName)
+
+	for attempt := 0; attempt <= r.policy.MaxRetries; attempt++ {
+		if !breaker.allow() {
+			return nil, fmt.Errorf("%w: model %q", ErrCircuitOpen, modelName)
+		}
+
+		out, err := fn(ctx)
+		if err == nil {
+			breaker.recordSuccess()
+			if attempt > 0 {
+				log.Printf("generate succeeded on retry attempt %d", attempt+1)
+			}
+			return out, nil
+		}
+
+		breaker.recordFailure()
+
+		if !isRetryableError(err) || attempt == r.policy.MaxRetries {
+			return nil, fmt.Errorf("after %d attempts: %w", attempt+1, err)
+		}
+
+		delay := r.backoff(attempt)
+		log.Printf("generate attempt %d failed: %v, retrying in %v", attempt+1, err, delay)
+		if err := r.clock.Sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("generate retry canceled: %w", err)
+		}
+	}
+
+	return nil, errors.New("retrier: unexpected error")
+}
+
+// backoff returns a full-jitter exponential delay for attempt: a value drawn
+// uniformly from [0, BaseDelay*2^attempt], capped at MaxDelay.
+func (r *Retrier) backoff(attempt int) time.Duration {
+	capped := r.policy.MaxDelay
+	if shifted := r.policy.BaseDelay << uint(attempt); shifted > 0 && shifted < capped {
+		capped = shifted
+	}
+	if capped <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(capped) + 1))
+}
+
+// isRetryableError reports whether err is transient: a timeout/temporary
+// net.Error, or a provider API error carrying a retryable HTTP status
+// (429 rate-limited, or a 5xx server error).
+//
+// The *openai.APIError branch only classifies errors from the openai /
+// azure-openai providers (it mirrors the HTTPStatusCode field eino-ext's
+// openai component surfaces from the underlying go-openai client); errors
+// from other registered providers (ollama, anthropic) fall through to the
+// net.Error check above and are otherwise treated as non-retryable until
+// those providers' equivalent error types are classified here too.
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout() || netErr.Temporary()
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.HTTPStatusCode {
+		case 429, 500, 502, 503, 504:
+			return true
+		}
+	}
+
+	return false
+}
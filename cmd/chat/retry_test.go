@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.Advance(d)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", &fakeNetError{timeout: true}, true},
+		{"temporary", &fakeNetError{temporary: true}, true},
+		{"nonRetryableNet", &fakeNetError{}, false},
+		{"generic", errors.New("other"), false},
+		{"rateLimited", &openai.APIError{HTTPStatusCode: 429}, true},
+		{"serverError", &openai.APIError{HTTPStatusCode: 503}, true},
+		{"badRequest", &openai.APIError{HTTPStatusCode: 400}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Fatalf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	clk := newFakeClock()
+	policy := RetryPolicy{FailureThreshold: 3, Window: time.Minute, CooldownPeriod: time.Second}
+	b := newCircuitBreaker(policy, clk)
+
+	for i := 0; i < 2; i++ {
+		if !b.allow() {
+			t.Fatalf("expected breaker to allow call %d before threshold", i+1)
+		}
+		b.recordFailure()
+	}
+
+	if !b.allow() {
+		t.Fatalf("expected breaker to still allow the threshold-th call")
+	}
+	b.recordFailure()
+
+	if b.allow() {
+		t.Fatalf("expected breaker to be open after %d consecutive failures", policy.FailureThreshold)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterCooldown(t *testing.T) {
+	clk := newFakeClock()
+	policy := RetryPolicy{FailureThreshold: 1, Window: time.Minute, CooldownPeriod: 10 * time.Second}
+	b := newCircuitBreaker(policy, clk)
+
+	b.allow()
+	b.recordFailure()
+	if b.allow() {
+		t.Fatalf("expected breaker to be open immediately after tripping")
+	}
+
+	clk.Advance(10 * time.Second)
+	if !b.allow() {
+		t.Fatalf("expected breaker to allow a half-open probe after cooldown")
+	}
+
+	b.recordSuccess()
+	if !b.allow() {
+		t.Fatalf("expected breaker to be closed after a successful probe")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	clk := newFakeClock()
+	policy := RetryPolicy{FailureThreshold: 1, Window: time.Minute, CooldownPeriod: 10 * time.Second}
+	b := newCircuitBreaker(policy, clk)
+
+	b.allow()
+	b.recordFailure()
+	clk.Advance(10 * time.Second)
+
+	if !b.allow() {
+		t.Fatalf("expected half-open probe to be allowed")
+	}
+	b.recordFailure()
+
+	if b.allow() {
+		t.Fatalf("expected breaker to re-open immediately after a failed probe")
+	}
+
+	clk.Advance(10 * time.Second)
+	if !b.allow() {
+		t.Fatalf("expected breaker to allow another probe after the second cooldown")
+	}
+}
+
+func TestRetrier_Generate_RetriesThenSucceeds(t *testing.T) {
+	clk := newFakeClock()
+	r := NewRetrier(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second, FailureThreshold: 10, Window: time.Minute, CooldownPeriod: time.Minute})
+	r.clock = clk
+
+	message := schema.AssistantMessage("ok", nil)
+	var calls atomic.Int32
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+			if calls.Add(1) == 1 {
+				return nil, &openai.APIError{HTTPStatusCode: 503}
+			}
+			return message, nil
+		},
+	}
+
+	got, err := r.Generate(context.Background(), "gpt-test", mockModel, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != message {
+		t.Fatalf("expected message %p, got %p", message, got)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 generate attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetrier_Generate_NonRetryableStopsImmediately(t *testing.T) {
+	clk := newFakeClock()
+	r := NewRetrier(DefaultRetryPolicy())
+	r.clock = clk
+
+	baseErr := errors.New("fatal")
+	var calls atomic.Int32
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+			calls.Add(1)
+			return nil, baseErr
+		},
+	}
+
+	_, err := r.Generate(context.Background(), "gpt-test", mockModel, nil)
+	if err == nil || !errors.Is(err, baseErr) {
+		t.Fatalf("expected error wrapping baseErr, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 generate attempt, got %d", calls.Load())
+	}
+}
+
+func TestRetrier_Generate_CircuitOpensAndRecovers(t *testing.T) {
+	clk := newFakeClock()
+	policy := RetryPolicy{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, FailureThreshold: 2, Window: time.Minute, CooldownPeriod: 5 * time.Second}
+	r := NewRetrier(policy)
+	r.clock = clk
+
+	baseErr := &openai.APIError{HTTPStatusCode: 500}
+	var calls atomic.Int32
+	mockModel := &mockToolCallingChatModel{
+		generateFn: func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+			calls.Add(1)
+			return nil, baseErr
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Generate(context.Background(), "gpt-test", mockModel, nil); err == nil {
+			t.Fatalf("expected error on failing attempt %d", i+1)
+		}
+	}
+
+	if _, err := r.Generate(context.Background(), "gpt-test", mockModel, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen once the breaker trips, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the short-circuited call not to reach the model, got %d calls", calls.Load())
+	}
+
+	clk.Advance(5 * time.Second)
+
+	message := schema.AssistantMessage("recovered", nil)
+	mockModel.generateFn = func(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+		return message, nil
+	}
+
+	got, err := r.Generate(context.Background(), "gpt-test", mockModel, nil)
+	if err != nil {
+		t.Fatalf("expected the half-open probe to succeed, got %v", err)
+	}
+	if got != message {
+		t.Fatalf("expected message %p, got %p", message, got)
+	}
+}
@@ -4,16 +4,16 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
+	"strings"
 
 	"github.com/cloudwego/eino/schema"
 )
 
-func reportStream(sr *schema.StreamReader[*schema.Message]) error {
-	return reportStreamWithContext(context.Background(), sr, os.Stdout)
-}
-
-func reportStreamWithContext(ctx context.Context, sr *schema.StreamReader[*schema.Message], writer io.Writer) error {
+// reportStreamWithContext forwards each chunk's content to writer as it
+// arrives, while also reassembling any streamed ToolCall deltas via a
+// StreamAggregator. It returns the fully aggregated message once the stream
+// reaches EOF.
+func reportStreamWithContext(ctx context.Context, sr *schema.StreamReader[*schema.Message], writer io.Writer) (*schema.Message, error) {
 	sr.SetAutomaticClose()
 
 	// 创建一个通道来监听上下文取消
@@ -31,18 +31,110 @@ func reportStreamWithContext(ctx context.Context, sr *schema.StreamReader[*schem
 		}
 	}()
 
+	agg := newStreamAggregator()
+
 	for {
 		chunk, err := sr.Recv()
 		if err == io.EOF {
-			return nil
+			return agg.Message(), nil
 		}
 		if err != nil {
-			return fmt.Errorf("stream error: %w", err)
+			return nil, fmt.Errorf("stream error: %w", err)
 		}
-		if chunk != nil && chunk.Content != "" {
+		if chunk == nil {
+			continue
+		}
+
+		agg.Add(chunk)
+
+		if chunk.Content != "" {
 			if _, err := fmt.Fprint(writer, chunk.Content); err != nil {
-				return fmt.Errorf("write error: %w", err)
+				return nil, fmt.Errorf("write error: %w", err)
 			}
 		}
 	}
 }
+
+// StreamAggregator reassembles a sequence of streamed message chunks into a
+// single *schema.Message, accumulating ToolCall fragments by their Index:
+// Function.Arguments deltas are concatenated and ID/Name are merged as they
+// arrive.
+type StreamAggregator struct {
+	content      strings.Builder
+	toolCalls    map[int]*schema.ToolCall
+	order        []int
+	finishReason string
+	usage        schema.TokenUsage
+	hasUsage     bool
+}
+
+func newStreamAggregator() *StreamAggregator {
+	return &StreamAggregator{toolCalls: make(map[int]*schema.ToolCall)}
+}
+
+// Add folds one stream chunk into the aggregator's running state.
+func (a *StreamAggregator) Add(chunk *schema.Message) {
+	if chunk == nil {
+		return
+	}
+
+	a.content.WriteString(chunk.Content)
+
+	for _, delta := range chunk.ToolCalls {
+		idx := 0
+		if delta.Index != nil {
+			idx = *delta.Index
+		}
+
+		call, ok := a.toolCalls[idx]
+		if !ok {
+			call = &schema.ToolCall{Index: delta.Index}
+			a.toolCalls[idx] = call
+			a.order = append(a.order, idx)
+		}
+
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		if delta.Type != "" {
+			call.Type = delta.Type
+		}
+		if delta.Function.Name != "" {
+			call.Function.Name = delta.Function.Name
+		}
+		call.Function.Arguments += delta.Function.Arguments
+	}
+
+	if chunk.ResponseMeta != nil {
+		if chunk.ResponseMeta.FinishReason != "" {
+			a.finishReason = chunk.ResponseMeta.FinishReason
+		}
+		if u := chunk.ResponseMeta.Usage; u != nil {
+			a.hasUsage = true
+			a.usage.PromptTokens += u.PromptTokens
+			a.usage.CompletionTokens += u.CompletionTokens
+			a.usage.TotalTokens += u.TotalTokens
+		}
+	}
+}
+
+// Message returns the message assembled so far: accumulated content, any
+// reassembled tool calls in first-seen index order, and a ResponseMeta
+// carrying the last seen finish reason and the summed per-chunk usage.
+func (a *StreamAggregator) Message() *schema.Message {
+	msg := schema.AssistantMessage(a.content.String(), nil)
+	for _, idx := range a.order {
+		msg.ToolCalls = append(msg.ToolCalls, *a.toolCalls[idx])
+	}
+
+	if a.finishReason != "" || a.hasUsage {
+		meta := &schema.ResponseMeta{FinishReason: a.finishReason}
+		if a.hasUsage {
+			usage := a.usage
+			meta.Usage = &usage
+		}
+		msg.ResponseMeta = meta
+	}
+
+	return msg
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func idx(i int) *int { return &i }
+
+func TestReportStreamWithContext_ReassemblesToolCall(t *testing.T) {
+	reader, writer := schema.Pipe[*schema.Message](4)
+
+	go func() {
+		defer writer.Close()
+		writer.Send(&schema.Message{
+			Role:    schema.Assistant,
+			Content: "Sure, ",
+			ToolCalls: []schema.ToolCall{
+				{Index: idx(0), ID: "call-1", Type: "function", Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"cit`}},
+			},
+		}, nil)
+		writer.Send(&schema.Message{
+			Role:    schema.Assistant,
+			Content: "let me check.",
+			ToolCalls: []schema.ToolCall{
+				{Index: idx(0), Function: schema.FunctionCall{Arguments: `y":"Beij`}},
+			},
+		}, nil)
+		writer.Send(&schema.Message{
+			Role: schema.Assistant,
+			ToolCalls: []schema.ToolCall{
+				{Index: idx(0), Function: schema.FunctionCall{Arguments: `ing"}`}},
+			},
+		}, nil)
+	}()
+
+	var buf bytes.Buffer
+	got, err := reportStreamWithContext(context.Background(), reader, &buf)
+	if err != nil {
+		t.Fatalf("reportStreamWithContext returned unexpected error: %v", err)
+	}
+
+	if buf.String() != "Sure, let me check." {
+		t.Fatalf("unexpected forwarded content: %q", buf.String())
+	}
+
+	if got.Content != "Sure, let me check." {
+		t.Fatalf("unexpected aggregated content: %q", got.Content)
+	}
+
+	if len(got.ToolCalls) != 1 {
+		t.Fatalf("expected 1 aggregated tool call, got %d", len(got.ToolCalls))
+	}
+
+	call := got.ToolCalls[0]
+	if call.ID != "call-1" || call.Function.Name != "get_weather" {
+		t.Fatalf("unexpected merged tool call: %+v", call)
+	}
+
+	var args map[string]string
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+		t.Fatalf("reassembled arguments did not parse as JSON: %v (%q)", err, call.Function.Arguments)
+	}
+	if args["city"] != "Beijing" {
+		t.Fatalf("unexpected reassembled arguments: %+v", args)
+	}
+}
+
+func TestReportStreamWithContext_NoToolCalls(t *testing.T) {
+	reader, writer := schema.Pipe[*schema.Message](2)
+
+	go func() {
+		defer writer.Close()
+		writer.Send(schema.AssistantMessage("hello", nil), nil)
+		writer.Send(schema.AssistantMessage(" world", nil), nil)
+	}()
+
+	var buf bytes.Buffer
+	got, err := reportStreamWithContext(context.Background(), reader, &buf)
+	if err != nil {
+		t.Fatalf("reportStreamWithContext returned unexpected error: %v", err)
+	}
+
+	if buf.String() != "hello world" {
+		t.Fatalf("unexpected forwarded content: %q", buf.String())
+	}
+	if got.Content != "hello world" {
+		t.Fatalf("unexpected aggregated content: %q", got.Content)
+	}
+	if len(got.ToolCalls) != 0 {
+		t.Fatalf("expected no tool calls, got %d", len(got.ToolCalls))
+	}
+}
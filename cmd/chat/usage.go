@@ -0,0 +1,63 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// TokenUsage mirrors schema.TokenUsage for a single call.
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// InvokeResponse is the structured result of a generate or stream call,
+// carrying the model's message alongside the bookkeeping callers typically
+// need: why generation stopped, how many tokens it cost, and any tool calls
+// requested.
+type InvokeResponse struct {
+	Message      *schema.Message
+	FinishReason string
+	TokenUsage   TokenUsage
+	ToolCalls    []schema.ToolCall
+}
+
+// UsageCollector accumulates TokenUsage across calls so a long-running
+// process can report cumulative token spend for a session.
+type UsageCollector struct {
+	mu     sync.Mutex
+	totals TokenUsage
+}
+
+// NewUsageCollector returns an empty UsageCollector.
+func NewUsageCollector() *UsageCollector {
+	return &UsageCollector{}
+}
+
+// Add folds u into the running totals.
+func (c *UsageCollector) Add(u TokenUsage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.totals.PromptTokens += u.PromptTokens
+	c.totals.CompletionTokens += u.CompletionTokens
+	c.totals.TotalTokens += u.TotalTokens
+}
+
+// Totals returns the accumulated usage so far.
+func (c *UsageCollector) Totals() TokenUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.totals
+}
+
+// defaultUsageCollector is the process-wide collector updated by generate and
+// generateStream.
+var defaultUsageCollector = NewUsageCollector()
+
+// DefaultUsageCollector returns the process-wide UsageCollector that generate
+// and generateStream report into.
+func DefaultUsageCollector() *UsageCollector {
+	return defaultUsageCollector
+}